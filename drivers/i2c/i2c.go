@@ -0,0 +1,98 @@
+package i2c
+
+import (
+	"io"
+	"sync"
+)
+
+const (
+	// BusNotInitialized is the initial value for a bus
+	BusNotInitialized = -1
+
+	// AddressNotInitialized is the initial value for an address
+	AddressNotInitialized = -1
+)
+
+// I2cOperations are the operations available to an I2C device on a single, already-addressed
+// bus: arbitrary Read/Write plus register helpers. ReadBlockData reads n bytes starting at reg
+// in a single SMBus transaction instead of the Write(reg)+Read(n) pair otherwise required,
+// halving the syscalls per register read.
+type I2cOperations interface {
+	io.ReadWriteCloser
+	ReadBlockData(reg byte, n int) ([]byte, error)
+}
+
+// I2cDevice is the interface to a specific i2c bus.
+type I2cDevice interface {
+	I2cOperations
+	SetAddress(int) error
+}
+
+// Connector lets Adaptors provide the interface for Drivers to get access to the I2C buses on
+// platforms that support I2C.
+type Connector interface {
+	// GetConnection returns a connection to device at the specified address and bus. Bus
+	// numbering starts at index 0, the range of valid buses is platform specific.
+	GetConnection(address int, bus int) (device Connection, err error)
+
+	// GetDefaultBus returns the default I2C bus index
+	GetDefaultBus() int
+}
+
+// Connection is a connection to an I2C device with a specified address on a specific bus.
+// Implements I2cOperations to talk to the device, wrapping the calls in SetAddress to always
+// target the specified device. Provided by an Adaptor by implementing the Connector interface.
+type Connection I2cOperations
+
+type i2cConnection struct {
+	bus     I2cDevice
+	address int
+	mutex   *sync.Mutex
+}
+
+// NewConnection creates and returns a new connection to a specific i2c device on a bus and
+// address.
+func NewConnection(bus I2cDevice, address int) (connection *i2cConnection) {
+	return &i2cConnection{bus: bus, address: address, mutex: &sync.Mutex{}}
+}
+
+// Read data from an i2c device.
+func (c *i2cConnection) Read(data []byte) (read int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err = c.bus.SetAddress(c.address); err != nil {
+		return 0, err
+	}
+	return c.bus.Read(data)
+}
+
+// Write data to an i2c device.
+func (c *i2cConnection) Write(data []byte) (written int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err = c.bus.SetAddress(c.address); err != nil {
+		return 0, err
+	}
+	return c.bus.Write(data)
+}
+
+// ReadBlockData reads n bytes starting at reg in a single SMBus transaction.
+func (c *i2cConnection) ReadBlockData(reg byte, n int) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.bus.SetAddress(c.address); err != nil {
+		return nil, err
+	}
+	return c.bus.ReadBlockData(reg, n)
+}
+
+// Close connection to i2c device.
+func (c *i2cConnection) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.bus.Close()
+}
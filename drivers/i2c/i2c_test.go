@@ -0,0 +1,67 @@
+package i2c
+
+import "testing"
+
+func TestConfigDefaultsToCaller(t *testing.T) {
+	c := NewConfig()
+
+	if got := c.GetBusOrDefault(3); got != 3 {
+		t.Errorf("GetBusOrDefault() = %d, want 3 (caller default, bus never set)", got)
+	}
+	if got := c.GetAddressOrDefault(0x40); got != 0x40 {
+		t.Errorf("GetAddressOrDefault() = %d, want 0x40 (caller default, address never set)", got)
+	}
+
+	c.WithBus(2)
+	c.WithAddress(0x41)
+
+	if got := c.GetBusOrDefault(3); got != 2 {
+		t.Errorf("GetBusOrDefault() = %d, want 2 (explicitly set)", got)
+	}
+	if got := c.GetAddressOrDefault(0x40); got != 0x41 {
+		t.Errorf("GetAddressOrDefault() = %d, want 0x41 (explicitly set)", got)
+	}
+}
+
+type fakeI2cDevice struct {
+	addresses   []int
+	blockData   []byte
+	blockDataN  int
+	blockDataAt byte
+}
+
+func (f *fakeI2cDevice) SetAddress(address int) error {
+	f.addresses = append(f.addresses, address)
+	return nil
+}
+
+func (f *fakeI2cDevice) Read(b []byte) (int, error) { return len(b), nil }
+
+func (f *fakeI2cDevice) Write(b []byte) (int, error) { return len(b), nil }
+
+func (f *fakeI2cDevice) ReadBlockData(reg byte, n int) ([]byte, error) {
+	f.blockDataAt = reg
+	f.blockDataN = n
+	return f.blockData, nil
+}
+
+func (f *fakeI2cDevice) Close() error { return nil }
+
+func TestConnectionReadBlockDataSetsAddressFirst(t *testing.T) {
+	bus := &fakeI2cDevice{blockData: []byte{0x12, 0x34}}
+	conn := NewConnection(bus, 0x40)
+
+	data, err := conn.ReadBlockData(BUSVOLTAGE_REG, 2)
+	if err != nil {
+		t.Fatalf("ReadBlockData() error = %v", err)
+	}
+	if len(bus.addresses) != 1 || bus.addresses[0] != 0x40 {
+		t.Errorf("bus.addresses = %v, want [0x40] (SetAddress must run before the transaction)", bus.addresses)
+	}
+	if bus.blockDataAt != BUSVOLTAGE_REG || bus.blockDataN != 2 {
+		t.Errorf("bus.ReadBlockData called with (%#x, %d), want (%#x, 2)", bus.blockDataAt, bus.blockDataN, BUSVOLTAGE_REG)
+	}
+	if data[0] != 0x12 || data[1] != 0x34 {
+		t.Errorf("ReadBlockData() = %v, want [0x12 0x34]", data)
+	}
+}
@@ -0,0 +1,683 @@
+package i2c
+
+// INAxxxDriver is a driver for the Texas Instruments INA2xx family of bi-directional
+// current/power monitors with an I2C/SMBUS interface: INA219, INA220, INA226 and INA230.
+// The chips share a register map and a common set of derived measurements (bus voltage,
+// shunt voltage, current and power); what differs between them is captured in an
+// inaDescriptor selected by the New*Driver constructor used.
+//
+// INA219 datasheet: http://www.ti.com/product/INA219
+// INA220 datasheet: http://www.ti.com/product/INA220
+// INA226 datasheet: http://www.ti.com/product/INA226
+// INA230 datasheet: http://www.ti.com/product/INA230
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const inaI2CAddress = 0x40
+
+// Registers address map, shared by the whole INA2xx family. INA219/INA220 do not implement
+// MASKENABLE_REG/ALERTLIMIT_REG (no Alert Function) or the ID registers.
+const (
+	CONFIG_REG         byte = 0x00
+	SHUNTVOLTAGE_REG   byte = 0x01
+	BUSVOLTAGE_REG     byte = 0x02
+	POWER_REG          byte = 0x03
+	CURRENT_REG        byte = 0x04
+	CALIBRATION_REG    byte = 0x05
+	MASKENABLE_REG     byte = 0x06
+	ALERTLIMIT_REG     byte = 0x07
+	MANUFACTURERID_REG byte = 0xFE
+	DIEID_REG          byte = 0xFF
+)
+
+// Configuration register helper constants for INA226/INA230
+//
+//   15   14  13  12    11     10     9       8       7       6      5      4      3      2     1     0
+//  _____ ___ ___ ___ ______ ______ ______ _______ _______ _______ ______ ______ ______ _____ _____ _____
+// |     |   |   |   |      |      |      |       |       |       |      |      |      |     |     |     |
+// | RST | - | - | - | AVG2 | AVG1 | AVG0 |VBUSCT2|VBUSCT1|VBUSCT0|VSHCT2|VSHCT1|VSHCT0|MODE3|MODE2|MODE1|
+// |_____|__ |___|___|______|______|______|_______|_______|_______|______|______|______|_____|_____|_____|
+
+const (
+	// MODE: operating mode (3-bit)
+	INA226_MODE_POWER_DOWN     uint16 = 0x00
+	INA226_MODE_SHUNT_TRIG     uint16 = 0x01
+	INA226_MODE_BUS_TRIG       uint16 = 0x02
+	INA226_MODE_SHUNT_BUS_TRIG uint16 = 0x03
+	INA226_MODE_ADC_OFF        uint16 = 0x04
+	INA226_MODE_SHUNT_CONT     uint16 = 0x05
+	INA226_MODE_BUS_CONT       uint16 = 0x06
+	INA226_MODE_SHUNT_BUS_CONT uint16 = 0x07
+
+	// VSHCT: shunt voltage conversion time (3-bit)
+	INA226_SHUNT_CONV_TIME_140US  uint16 = 0x00 << 3
+	INA226_SHUNT_CONV_TIME_204US  uint16 = 0x01 << 3
+	INA226_SHUNT_CONV_TIME_332US  uint16 = 0x02 << 3
+	INA226_SHUNT_CONV_TIME_588US  uint16 = 0x03 << 3
+	INA226_SHUNT_CONV_TIME_1100US uint16 = 0x04 << 3
+	INA226_SHUNT_CONV_TIME_2116US uint16 = 0x05 << 3
+	INA226_SHUNT_CONV_TIME_4156US uint16 = 0x06 << 3
+	INA226_SHUNT_CONV_TIME_8244US uint16 = 0x07 << 3
+
+	// VBUSCT: bus voltage conversion time (3-bit)
+	INA226_BUS_CONV_TIME_140US  uint16 = 0x00 << 6
+	INA226_BUS_CONV_TIME_204US  uint16 = 0x01 << 6
+	INA226_BUS_CONV_TIME_332US  uint16 = 0x02 << 6
+	INA226_BUS_CONV_TIME_588US  uint16 = 0x03 << 6
+	INA226_BUS_CONV_TIME_1100US uint16 = 0x04 << 6
+	INA226_BUS_CONV_TIME_2116US uint16 = 0x05 << 6
+	INA226_BUS_CONV_TIME_4156US uint16 = 0x06 << 6
+	INA226_BUS_CONV_TIME_8244US uint16 = 0x07 << 6
+
+	// AVG: averaging mode (3-bit)
+	INA226_AVERAGES_1    uint16 = 0x00 << 9
+	INA226_AVERAGES_4    uint16 = 0x01 << 9
+	INA226_AVERAGES_16   uint16 = 0x02 << 9
+	INA226_AVERAGES_64   uint16 = 0x03 << 9
+	INA226_AVERAGES_128  uint16 = 0x04 << 9
+	INA226_AVERAGES_256  uint16 = 0x05 << 9
+	INA226_AVERAGES_512  uint16 = 0x06 << 9
+	INA226_AVERAGES_1024 uint16 = 0x07 << 9
+
+	// RST bit
+	INA226_RST uint16 = 0x01 << 15
+)
+
+// Configuration register helper constants for INA219/INA220
+//
+//   15   14    13    12   11    10     9     8     7    6      5     4     3    2     1     0
+//  _____ ____ _____ ____ ____ _____ _____ _____ _____ _____ _____ _____ _____ _____ _____ _____
+// |     |    |      |    |    |     |     |     |     |     |     |     |     |     |     |     |
+// | RST | -  | BRNG | PG1| PG0|BADC4|BADC3|BADC2|BADC1|SADC4|SADC3|SADC2|SADC1|MODE3|MODE2|MODE1|
+// |_____|____|______|____|____|_____|_____|_____|_____|_____|_____|_____|_____|_____|_____|_____|
+
+const (
+	// MODE: operating mode (3-bit), same encoding as INA226
+	INA219_MODE_POWER_DOWN     uint16 = 0x00
+	INA219_MODE_SHUNT_TRIG     uint16 = 0x01
+	INA219_MODE_BUS_TRIG       uint16 = 0x02
+	INA219_MODE_SHUNT_BUS_TRIG uint16 = 0x03
+	INA219_MODE_ADC_OFF        uint16 = 0x04
+	INA219_MODE_SHUNT_CONT     uint16 = 0x05
+	INA219_MODE_BUS_CONT       uint16 = 0x06
+	INA219_MODE_SHUNT_BUS_CONT uint16 = 0x07
+
+	// SADC: shunt ADC resolution/averaging (4-bit)
+	INA219_SADC_9BIT    uint16 = 0x00 << 3
+	INA219_SADC_10BIT   uint16 = 0x01 << 3
+	INA219_SADC_11BIT   uint16 = 0x02 << 3
+	INA219_SADC_12BIT   uint16 = 0x03 << 3
+	INA219_SADC_2SAMPLE uint16 = 0x09 << 3
+	INA219_SADC_4SAMPLE uint16 = 0x0A << 3
+	INA219_SADC_8SAMPLE uint16 = 0x0B << 3
+
+	// BADC: bus ADC resolution/averaging (4-bit)
+	INA219_BADC_9BIT    uint16 = 0x00 << 7
+	INA219_BADC_10BIT   uint16 = 0x01 << 7
+	INA219_BADC_11BIT   uint16 = 0x02 << 7
+	INA219_BADC_12BIT   uint16 = 0x03 << 7
+	INA219_BADC_2SAMPLE uint16 = 0x09 << 7
+	INA219_BADC_4SAMPLE uint16 = 0x0A << 7
+	INA219_BADC_8SAMPLE uint16 = 0x0B << 7
+
+	// PGA: shunt voltage gain (2-bit)
+	INA219_PGA_40MV  uint16 = 0x00 << 11
+	INA219_PGA_80MV  uint16 = 0x01 << 11
+	INA219_PGA_160MV uint16 = 0x02 << 11
+	INA219_PGA_320MV uint16 = 0x03 << 11
+
+	// BRNG: bus voltage range (1-bit)
+	INA219_BRNG_16V uint16 = 0x00 << 13
+	INA219_BRNG_32V uint16 = 0x01 << 13
+
+	// RST bit
+	INA219_RST uint16 = 0x01 << 15
+)
+
+// Mask/Enable register bits (Alert Function subsystem). Only implemented by INA226/INA230.
+const (
+	// Alert Function bits: select which condition the ALERT pin and AFF flag react to.
+	INA226_SOL_BIT  uint16 = 0x01 << 15 // Shunt Voltage Over-Voltage
+	INA226_SUL_BIT  uint16 = 0x01 << 14 // Shunt Voltage Under-Voltage
+	INA226_BOL_BIT  uint16 = 0x01 << 13 // Bus Voltage Over-Voltage
+	INA226_BUL_BIT  uint16 = 0x01 << 12 // Bus Voltage Under-Voltage
+	INA226_POL_BIT  uint16 = 0x01 << 11 // Power Over-Limit
+	INA226_CNVR_BIT uint16 = 0x01 << 10 // Conversion Ready
+
+	// Flag bits: set by the device, cleared by reading the Mask/Enable register.
+	INA226_AFF_BIT  uint16 = 0x01 << 4 // Alert Function Flag
+	INA226_CVRF_BIT uint16 = 0x01 << 3 // Conversion Ready Flag
+	INA226_OVF_BIT  uint16 = 0x01 << 2 // Math Overflow Flag
+
+	// ALERT pin behaviour
+	INA226_APOL_BIT uint16 = 0x01 << 1 // Alert Polarity (1 = active-high)
+	INA226_LEN_BIT  uint16 = 0x01 << 0 // Alert Latch Enable
+)
+
+// AlertKind identifies which Alert Function the Mask/Enable register should monitor.
+type AlertKind int
+
+const (
+	// SOL fires when the shunt voltage rises above the configured limit.
+	SOL AlertKind = iota
+	// SUL fires when the shunt voltage falls below the configured limit.
+	SUL
+	// BOL fires when the bus voltage rises above the configured limit.
+	BOL
+	// BUL fires when the bus voltage falls below the configured limit.
+	BUL
+	// POL fires when the calculated power rises above the configured limit.
+	POL
+	// CNVR fires when a conversion completes.
+	CNVR
+)
+
+// inaDescriptor captures the constants that differ across the INA2xx family: the physical
+// scale of a bus/shunt voltage LSB (in millivolts, matching this driver's existing Read*
+// convention), the number of low bits the Bus Voltage register reserves for flags that must be
+// shifted off before scaling, the calibration constant used to derive the Current register
+// LSB, and which optional register blocks the chip implements.
+type inaDescriptor struct {
+	chip             string
+	busVoltageLSB    float64 // millivolts per bus voltage LSB
+	busVoltageShift  uint    // low bits of BUSVOLTAGE_REG to discard before scaling (CNVR/OVF flags)
+	shuntVoltageLSB  float64 // millivolts per shunt voltage LSB
+	calibrationConst float64
+	powerLSBFactor   float64 // Power register to watts: powerLSBFactor * currentLSB * raw
+	hasAlertFunction bool    // Mask/Enable + Alert Limit registers (INA226/INA230 only)
+	hasIDRegisters   bool    // Manufacturer ID / Die ID registers (INA226/INA230 only)
+}
+
+var (
+	ina219Descriptor = inaDescriptor{chip: "INA219", busVoltageLSB: 4, busVoltageShift: 3, shuntVoltageLSB: 0.01, calibrationConst: 0.04096, powerLSBFactor: 20}
+	ina220Descriptor = inaDescriptor{chip: "INA220", busVoltageLSB: 4, busVoltageShift: 3, shuntVoltageLSB: 0.01, calibrationConst: 0.04096, powerLSBFactor: 20}
+	ina226Descriptor = inaDescriptor{chip: "INA226", busVoltageLSB: 1.25, shuntVoltageLSB: 0.0025, calibrationConst: 0.00512, powerLSBFactor: 25, hasAlertFunction: true, hasIDRegisters: true}
+	ina230Descriptor = inaDescriptor{chip: "INA230", busVoltageLSB: 1.25, shuntVoltageLSB: 0.0025, calibrationConst: 0.00512, powerLSBFactor: 25, hasAlertFunction: true, hasIDRegisters: true}
+)
+
+type LoadSet struct {
+	rShunt, iMax, vBusMax, vShuntMax, currentLSB float64
+
+	// currentResolution caches CurrentResolution's result as computed by Calibrate, so
+	// ReadShuntCurrent doesn't have to re-read CALIBRATION_REG over I2C on every call.
+	currentResolution float64
+}
+
+// INAxxxDriver is the shared implementation behind NewINA219Driver, NewINA220Driver,
+// NewINA226Driver and NewINA230Driver.
+type INAxxxDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	Config
+	gobot.Eventer
+	chip    inaDescriptor
+	loadSet LoadSet
+
+	pollMu  sync.Mutex
+	halt    chan struct{}
+	polling bool
+}
+
+// INA226Driver is kept as an alias of INAxxxDriver for source compatibility; new code should
+// refer to the driver as INAxxxDriver.
+type INA226Driver = INAxxxDriver
+
+func newINAxxxDriver(chip inaDescriptor, c Connector, options ...func(Config)) *INAxxxDriver {
+	i := &INAxxxDriver{
+		name:      gobot.DefaultName(chip.chip),
+		connector: c,
+		Config:    NewConfig(),
+		Eventer:   gobot.NewEventer(),
+		chip:      chip,
+	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	i.AddEvent("alert")
+	i.AddEvent("conversionReady")
+	i.AddEvent("mathOverflow")
+
+	return i
+}
+
+// NewINA219Driver creates a new driver for the Texas Instruments INA219.
+func NewINA219Driver(c Connector, options ...func(Config)) *INAxxxDriver {
+	return newINAxxxDriver(ina219Descriptor, c, options...)
+}
+
+// NewINA220Driver creates a new driver for the Texas Instruments INA220.
+func NewINA220Driver(c Connector, options ...func(Config)) *INAxxxDriver {
+	return newINAxxxDriver(ina220Descriptor, c, options...)
+}
+
+// NewINA226Driver creates a new driver for the Texas Instruments INA226.
+func NewINA226Driver(c Connector, options ...func(Config)) *INAxxxDriver {
+	return newINAxxxDriver(ina226Descriptor, c, options...)
+}
+
+// NewINA230Driver creates a new driver for the Texas Instruments INA230.
+func NewINA230Driver(c Connector, options ...func(Config)) *INAxxxDriver {
+	return newINAxxxDriver(ina230Descriptor, c, options...)
+}
+
+// Name returns the name of the device.
+func (i *INAxxxDriver) Name() string {
+	return i.name
+}
+
+// SetName sets the name of the device.
+func (i *INAxxxDriver) SetName(name string) {
+	i.name = name
+}
+
+// Connection returns the connection of the device.
+func (i *INAxxxDriver) Connection() gobot.Connection {
+	return i.connector.(gobot.Connection)
+}
+
+// Start initializes the device.
+func (i *INAxxxDriver) Start() error {
+	var err error
+	bus := i.GetBusOrDefault(i.connector.GetDefaultBus())
+	address := i.GetAddressOrDefault(int(inaI2CAddress))
+
+	if i.connection, err = i.connector.GetConnection(address, bus); err != nil {
+		return err
+	}
+
+	i.Configure()
+	return nil
+}
+
+// Halt stops alert polling, if running, and halts the device. It is safe to call more than
+// once, and safe to call concurrently with PollAlerts.
+func (i *INAxxxDriver) Halt() error {
+	i.pollMu.Lock()
+	defer i.pollMu.Unlock()
+
+	if i.polling {
+		close(i.halt)
+		i.polling = false
+	}
+	return nil
+}
+
+func wordToByteArray(w uint16) []byte {
+	buf := make([]byte, 2)
+	buf[0] = byte(w >> 8)
+	buf[1] = byte(w)
+	return buf
+}
+
+func (i *INAxxxDriver) Configure(confs ...uint16) error {
+	var configuration uint16
+
+	for _, conf := range confs {
+		configuration |= conf
+	}
+	var buf []byte
+	buf = append(buf, CONFIG_REG)
+	buf = append(buf, wordToByteArray(configuration)...)
+
+	_, err := i.connection.Write(buf)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *INAxxxDriver) Calibrate(rShuntValue float64, iMaxValue float64) error {
+	i.loadSet.rShunt = rShuntValue
+	i.loadSet.iMax = iMaxValue
+
+	currentLSB := i.loadSet.iMax / 32768
+	currentLSB *= 1000000 // transform to micro Ampere
+	// As described in the datasheet to simplify calculation we should approximate the current LSB number
+	// the method used is following described:
+	// first extract from the currentLSB normalized notation only the mantissa
+	currentLSB_mantissa := currentLSB / (math.Pow(10, math.Floor(math.Log10(currentLSB))))
+	// then apply the ceiling function and multiply for the exponent
+	currentLSB_approx := math.Ceil(currentLSB_mantissa) * math.Pow(10, math.Floor((math.Log10(currentLSB))))
+	currentLSB = currentLSB_approx / 1000000 //transform back to Ampere
+
+	calibrationValue := uint16(i.chip.calibrationConst / (currentLSB * i.loadSet.rShunt))
+	i.loadSet.currentLSB = currentLSB
+	i.loadSet.currentResolution = i.chip.calibrationConst / (float64(calibrationValue) * i.loadSet.rShunt)
+
+	var buf []byte
+	buf = append(buf, CALIBRATION_REG)
+	buf = append(buf, wordToByteArray(calibrationValue)...)
+
+	_, err := i.connection.Write(buf)
+	if err != nil {
+		return err
+	}
+	return nil
+
+}
+
+// Reset set the reset bit in the configure register and reset the ic.
+func (i *INAxxxDriver) Reset() error {
+	var buf []byte
+	buf = append(buf, CONFIG_REG)
+	buf = append(buf, wordToByteArray(INA226_RST)...)
+	_, err := i.connection.Write(buf)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// readRegister16 reads a 16-bit register in a single SMBus transaction via the Connection's
+// ReadBlockData, halving the syscalls per register read compared to the Write(reg)+Read(2)
+// pair this used to require. Mirrors the ReadBytes(addr, num) addition made to the embd
+// project's I2CBus; the device returned by sysfs.NewI2cDevice implements it via the
+// I2C_SMBUS_I2C_BLOCK_DATA ioctl.
+func (i *INAxxxDriver) readRegister16(reg byte) (uint16, error) {
+	buf, err := i.connection.ReadBlockData(reg, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
+
+func (i *INAxxxDriver) readConfigurationRegister() (uint16, error) {
+	confReg, err := i.readRegister16(CONFIG_REG)
+	if err != nil {
+		return 0, err
+	}
+	return confReg, nil
+}
+
+// CurrentResolution returns the Current register's LSB in amps, as cached by Calibrate. The
+// device must have been calibrated with Calibrate() first.
+func (i *INAxxxDriver) CurrentResolution() (float64, error) {
+	if i.loadSet.rShunt <= 0.0 {
+		return 0, fmt.Errorf("rShunt value: %f is not correct. Must be greater than 0", i.loadSet.rShunt)
+	}
+	return i.loadSet.currentResolution, nil
+}
+
+// ReadManufacturerRegister reads the Manufacturer ID register. Only INA226 and INA230
+// implement it; it returns an error for INA219/INA220.
+func (i *INAxxxDriver) ReadManufacturerRegister() (uint16, error) {
+	if !i.chip.hasIDRegisters {
+		return 0, fmt.Errorf("%s does not implement the Manufacturer ID register", i.chip.chip)
+	}
+	confReg, err := i.readRegister16(MANUFACTURERID_REG)
+	if err != nil {
+		return 0, err
+	}
+	return confReg, nil
+}
+
+// ReadBusVoltage returns the bus voltage in millivolts. On INA219/INA220 the Bus Voltage
+// register holds the reading in bits 15:3, with CNVR/OVF flags in the low bits, so those are
+// shifted off per inaDescriptor.busVoltageShift before scaling; INA226/INA230 use the whole
+// register and leave the shift at 0.
+func (i *INAxxxDriver) ReadBusVoltage() (float64, error) {
+	voltage, err := i.readRegister16(BUSVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(voltage>>i.chip.busVoltageShift) * i.chip.busVoltageLSB, nil
+}
+
+func (i *INAxxxDriver) ReadShuntVoltage() (float64, error) {
+	voltage, err := i.readRegister16(SHUNTVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(int16(voltage)) * i.chip.shuntVoltageLSB, nil
+}
+
+func (i *INAxxxDriver) ReadShuntCurrentRegister() (int16, error) {
+	currentRaw, err := i.readRegister16(CURRENT_REG)
+	if err != nil {
+		return 0, err
+	}
+	return int16(currentRaw), nil
+}
+
+func (i *INAxxxDriver) ReadShuntCurrent() (float64, error) {
+	currentRaw, err := i.ReadShuntCurrentRegister()
+	if err != nil {
+		return 0, err
+	}
+
+	currentResolution, err := i.CurrentResolution()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(currentRaw) * currentResolution, nil
+}
+
+// ReadPowerRegister returns the raw contents of the Power register.
+func (i *INAxxxDriver) ReadPowerRegister() (uint16, error) {
+	return i.readRegister16(POWER_REG)
+}
+
+// ReadPower returns the power on the rail in watts, computed from the Power register as
+// chip.powerLSBFactor * currentLSB * raw (per datasheet; the factor is 20 for INA219/INA220
+// and 25 for INA226/INA230). The device must have been calibrated with Calibrate() first.
+func (i *INAxxxDriver) ReadPower() (float64, error) {
+	if i.loadSet.currentLSB <= 0 {
+		return 0, fmt.Errorf("device must be calibrated with Calibrate() before reading power")
+	}
+
+	raw, err := i.ReadPowerRegister()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(raw) * i.chip.powerLSBFactor * i.loadSet.currentLSB, nil
+}
+
+// Readings is a single snapshot of bus voltage, shunt voltage, current and power, as returned
+// by ReadAll.
+type Readings struct {
+	BusVoltage   float64
+	ShuntVoltage float64
+	Current      float64
+	Power        float64
+}
+
+// ReadAll is a convenience that returns bus voltage, shunt voltage, current and power from a
+// single call, equivalent to calling each Read* method in turn. It makes no atomicity
+// guarantee: the device can complete a new conversion between any two of the underlying reads,
+// so the four values are not guaranteed to come from the same conversion cycle.
+func (i *INAxxxDriver) ReadAll() (Readings, error) {
+	busVoltage, err := i.ReadBusVoltage()
+	if err != nil {
+		return Readings{}, err
+	}
+
+	shuntVoltage, err := i.ReadShuntVoltage()
+	if err != nil {
+		return Readings{}, err
+	}
+
+	current, err := i.ReadShuntCurrent()
+	if err != nil {
+		return Readings{}, err
+	}
+
+	power, err := i.ReadPower()
+	if err != nil {
+		return Readings{}, err
+	}
+
+	return Readings{
+		BusVoltage:   busVoltage,
+		ShuntVoltage: shuntVoltage,
+		Current:      current,
+		Power:        power,
+	}, nil
+}
+
+func (i *INAxxxDriver) readMaskEnableRegister() (uint16, error) {
+	mask, err := i.readRegister16(MASKENABLE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return mask, nil
+}
+
+func (i *INAxxxDriver) writeMaskEnableRegister(mask uint16) error {
+	var buf []byte
+	buf = append(buf, MASKENABLE_REG)
+	buf = append(buf, wordToByteArray(mask)...)
+	_, err := i.connection.Write(buf)
+	return err
+}
+
+// alertSelectBits are the Mask/Enable bits that select which condition the ALERT pin and AFF
+// flag react to. The device has a single ALERTLIMIT_REG, so only one of them can be active at
+// a time; SetAlert clears all of them before setting the one requested.
+const alertSelectBits = INA226_SOL_BIT | INA226_SUL_BIT | INA226_BOL_BIT | INA226_BUL_BIT | INA226_POL_BIT | INA226_CNVR_BIT
+
+// SetAlert enables kind's Alert Function and programs the Alert Limit register so the device
+// asserts its ALERT pin once the corresponding reading crosses limit. limit is expressed in
+// the same unit as the matching Read* method (millivolts for SOL/SUL/BOL/BUL, watts for POL);
+// it is ignored for CNVR, which only needs its bit set in the Mask/Enable register. The device
+// has a single Alert Limit register shared by every kind, so only one alert can be active at a
+// time; calling SetAlert again with a different kind disables the previous one. Only INA226 and
+// INA230 implement the Alert Function; it returns an error for INA219/INA220.
+func (i *INAxxxDriver) SetAlert(kind AlertKind, limit float64) error {
+	if !i.chip.hasAlertFunction {
+		return fmt.Errorf("%s does not implement the Alert Function", i.chip.chip)
+	}
+
+	var bit, raw uint16
+
+	switch kind {
+	case SOL:
+		bit = INA226_SOL_BIT
+		raw = uint16(int16(limit / i.chip.shuntVoltageLSB))
+	case SUL:
+		bit = INA226_SUL_BIT
+		raw = uint16(int16(limit / i.chip.shuntVoltageLSB))
+	case BOL:
+		bit = INA226_BOL_BIT
+		raw = uint16(limit / i.chip.busVoltageLSB)
+	case BUL:
+		bit = INA226_BUL_BIT
+		raw = uint16(limit / i.chip.busVoltageLSB)
+	case POL:
+		if i.loadSet.currentLSB <= 0 {
+			return fmt.Errorf("device must be calibrated with Calibrate() before setting a power alert")
+		}
+		bit = INA226_POL_BIT
+		raw = uint16(limit / (i.chip.powerLSBFactor * i.loadSet.currentLSB))
+	case CNVR:
+		bit = INA226_CNVR_BIT
+	default:
+		return fmt.Errorf("unsupported alert kind: %v", kind)
+	}
+
+	mask, err := i.readMaskEnableRegister()
+	if err != nil {
+		return err
+	}
+	mask = mask&^alertSelectBits | bit
+	if err := i.writeMaskEnableRegister(mask); err != nil {
+		return err
+	}
+
+	if kind == CNVR {
+		return nil
+	}
+
+	var buf []byte
+	buf = append(buf, ALERTLIMIT_REG)
+	buf = append(buf, wordToByteArray(raw)...)
+	_, err = i.connection.Write(buf)
+	return err
+}
+
+// ConfigureAlertPin sets the ALERT pin's output polarity and whether an asserted alert latches
+// until the Mask/Enable register is read, without disturbing the Alert Function bits set by
+// SetAlert. Only INA226 and INA230 implement the Alert Function; it returns an error for
+// INA219/INA220.
+func (i *INAxxxDriver) ConfigureAlertPin(latch bool, activeHigh bool) error {
+	if !i.chip.hasAlertFunction {
+		return fmt.Errorf("%s does not implement the Alert Function", i.chip.chip)
+	}
+
+	mask, err := i.readMaskEnableRegister()
+	if err != nil {
+		return err
+	}
+
+	if latch {
+		mask |= INA226_LEN_BIT
+	} else {
+		mask &^= INA226_LEN_BIT
+	}
+
+	if activeHigh {
+		mask |= INA226_APOL_BIT
+	} else {
+		mask &^= INA226_APOL_BIT
+	}
+
+	return i.writeMaskEnableRegister(mask)
+}
+
+// PollAlerts starts a goroutine that reads the Mask/Enable register every interval and
+// publishes "alert", "conversionReady" and "mathOverflow" events, decoded from the AFF, CVRF
+// and OVF flags, for consumers to subscribe to via gobot.Eventer. Call Halt to stop polling.
+// Calling PollAlerts again while already polling is a no-op. Only INA226 and INA230 implement
+// the Alert Function; it is a no-op for INA219/INA220.
+func (i *INAxxxDriver) PollAlerts(interval time.Duration) {
+	if !i.chip.hasAlertFunction {
+		return
+	}
+
+	i.pollMu.Lock()
+	if i.polling {
+		i.pollMu.Unlock()
+		return
+	}
+	halt := make(chan struct{})
+	i.halt = halt
+	i.polling = true
+	i.pollMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				mask, err := i.readMaskEnableRegister()
+				if err != nil {
+					continue
+				}
+				if mask&INA226_AFF_BIT != 0 {
+					i.Publish("alert", mask)
+				}
+				if mask&INA226_CVRF_BIT != 0 {
+					i.Publish("conversionReady", mask)
+				}
+				if mask&INA226_OVF_BIT != 0 {
+					i.Publish("mathOverflow", mask)
+				}
+			case <-halt:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,146 @@
+package i2c
+
+import "testing"
+
+// fakeINAConnection answers readRegister16's ReadBlockData calls with canned register
+// contents, and records writes of [reg, high, low] back into registers so tests can observe
+// what a driver method wrote.
+type fakeINAConnection struct {
+	registers map[byte][]byte
+}
+
+func (f *fakeINAConnection) Read(b []byte) (int, error) { return len(b), nil }
+
+func (f *fakeINAConnection) Write(b []byte) (int, error) {
+	if len(b) >= 1 {
+		f.registers[b[0]] = append([]byte(nil), b[1:]...)
+	}
+	return len(b), nil
+}
+
+func (f *fakeINAConnection) ReadBlockData(reg byte, n int) ([]byte, error) {
+	if buf, ok := f.registers[reg]; ok {
+		return buf, nil
+	}
+	return make([]byte, n), nil
+}
+
+func (f *fakeINAConnection) Close() error { return nil }
+
+type fakeINAConnector struct {
+	connection Connection
+}
+
+func (f *fakeINAConnector) GetConnection(address int, bus int) (Connection, error) {
+	return f.connection, nil
+}
+
+func (f *fakeINAConnector) GetDefaultBus() int { return 0 }
+
+func newTestDriver(t *testing.T, chip inaDescriptor, registers map[byte][]byte) *INAxxxDriver {
+	t.Helper()
+
+	if registers == nil {
+		registers = map[byte][]byte{}
+	}
+	d := newINAxxxDriver(chip, &fakeINAConnector{connection: &fakeINAConnection{registers: registers}})
+	if err := d.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return d
+}
+
+// TestReadBusVoltage_INA219Shift covers the shared ReadBusVoltage path diverging between
+// chip families: INA219/INA220 pack the reading into bits 15:3 with CNVR/OVF flags in the low
+// bits, while INA226/INA230 use the whole register.
+func TestReadBusVoltage_INA219Shift(t *testing.T) {
+	// 1000 counts, with the CNVR flag (bit 0) set in the low bits that must be shifted off.
+	raw := wordToByteArray(uint16(1000<<3 | 0x01))
+
+	d := newTestDriver(t, ina219Descriptor, map[byte][]byte{BUSVOLTAGE_REG: raw})
+
+	got, err := d.ReadBusVoltage()
+	if err != nil {
+		t.Fatalf("ReadBusVoltage() error = %v", err)
+	}
+	if want := 4000.0; got != want {
+		t.Errorf("ReadBusVoltage() = %v, want %v (1000 counts * 4mV, flag bits discarded)", got, want)
+	}
+}
+
+func TestReadBusVoltage_INA226NoShift(t *testing.T) {
+	raw := wordToByteArray(uint16(1000))
+
+	d := newTestDriver(t, ina226Descriptor, map[byte][]byte{BUSVOLTAGE_REG: raw})
+
+	got, err := d.ReadBusVoltage()
+	if err != nil {
+		t.Fatalf("ReadBusVoltage() error = %v", err)
+	}
+	if want := 1250.0; got != want {
+		t.Errorf("ReadBusVoltage() = %v, want %v (1000 counts * 1.25mV)", got, want)
+	}
+}
+
+// TestReadPower_LSBFactor covers ReadPower's chip-specific factor: 20 for INA219/INA220, 25
+// for INA226/INA230, for the same raw register and currentLSB.
+func TestReadPower_LSBFactor(t *testing.T) {
+	raw := wordToByteArray(uint16(100))
+
+	cases := []struct {
+		name string
+		chip inaDescriptor
+		want float64
+	}{
+		{"INA219", ina219Descriptor, 100 * 20 * 0.001},
+		{"INA226", ina226Descriptor, 100 * 25 * 0.001},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := newTestDriver(t, c.chip, map[byte][]byte{POWER_REG: raw})
+			d.loadSet.currentLSB = 0.001
+
+			got, err := d.ReadPower()
+			if err != nil {
+				t.Fatalf("ReadPower() error = %v", err)
+			}
+			if got != c.want {
+				t.Errorf("ReadPower() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestSetAlertClearsOtherSelectBits covers the single shared ALERTLIMIT_REG: enabling a second
+// alert kind must disable the first, since the device can only honor one limit at a time.
+func TestSetAlertClearsOtherSelectBits(t *testing.T) {
+	registers := map[byte][]byte{}
+	d := newTestDriver(t, ina226Descriptor, registers)
+
+	if err := d.SetAlert(SOL, 100); err != nil {
+		t.Fatalf("SetAlert(SOL) error = %v", err)
+	}
+	if err := d.SetAlert(BOL, 200); err != nil {
+		t.Fatalf("SetAlert(BOL) error = %v", err)
+	}
+
+	mask := uint16(registers[MASKENABLE_REG][0])<<8 | uint16(registers[MASKENABLE_REG][1])
+	if mask&INA226_SOL_BIT != 0 {
+		t.Errorf("Mask/Enable register = %#x, SOL bit still set after SetAlert(BOL)", mask)
+	}
+	if mask&INA226_BOL_BIT == 0 {
+		t.Errorf("Mask/Enable register = %#x, want BOL bit set", mask)
+	}
+}
+
+func TestHaltIsSafeWithoutPolling(t *testing.T) {
+	d := newTestDriver(t, ina226Descriptor, nil)
+
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt() before PollAlerts error = %v", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("second Halt() error = %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package nanopineo
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -11,6 +12,10 @@ import (
 	"gobot.io/x/gobot/sysfs"
 )
 
+// DefaultPWMPeriod is the period, in nanoseconds, a PWM pin is configured with the first time
+// it is used unless overridden with SetPWMPeriod.
+const DefaultPWMPeriod = 10000000
+
 type sysfsPin struct {
 	pin    int
 	pwmPin int
@@ -18,40 +23,86 @@ type sysfsPin struct {
 
 // Adaptor represents a Gobot Adaptor for a Nano pi Neo
 type Adaptor struct {
-	name        string
-	board       string
-	pinmap      map[string]sysfsPin
-	digitalPins map[int]*sysfs.DigitalPin
-	pwmPins     map[int]*sysfs.PWMPin
-	i2cBuses    [2]i2c.I2cDevice
-	mutex       *sync.Mutex
+	name          string
+	board         string
+	pinmap        map[string]sysfsPin
+	defaultI2CBus int
+	pwmPeriod     uint32
+	pwmChip       int
+	digitalPins   map[int]*sysfs.DigitalPin
+	pwmPins       map[int]*sysfs.PWMPin
+	i2cBuses      [2]i2c.I2cDevice
+	mutex         *sync.Mutex
 }
 
-// NewAdaptor creates a Nano pi Neo Adaptor
+// NewAdaptor creates a Nano pi Neo Adaptor. It detects which member of the NanoPi family it is
+// running on from the device tree and picks the matching Descriptor; if the board can't be
+// identified (for example because it isn't running on real hardware) it falls back to the
+// original NanoPi Neo pin map.
 func NewAdaptor() *Adaptor {
+	descriptor, err := lookupDescriptor()
+	if err != nil {
+		descriptor = descriptors["friendlyarm,nanopi-neo"]
+	}
+
 	c := &Adaptor{
-		name:  gobot.DefaultName("Nano pi Neo"),
-		board: "nanopi-neo",
-		mutex: &sync.Mutex{},
+		name:          gobot.DefaultName("Nano pi Neo"),
+		board:         descriptor.Board,
+		pinmap:        descriptor.Pins,
+		defaultI2CBus: descriptor.DefaultI2CBus,
+		pwmPeriod:     DefaultPWMPeriod,
+		pwmChip:       -1,
+		mutex:         &sync.Mutex{},
 	}
 	c.digitalPins = make(map[int]*sysfs.DigitalPin)
 	c.pwmPins = make(map[int]*sysfs.PWMPin)
 
-	//c.setPins()
 	return c
 }
 
+// SetPWMPeriod overrides the period, in nanoseconds, that PWM pins are configured with the
+// first time they're used. It has no effect on PWM pins already exported.
+func (c *Adaptor) SetPWMPeriod(period uint32) {
+	c.pwmPeriod = period
+}
+
 // Name returns the name of the Adaptor
 func (c *Adaptor) Name() string { return c.name }
 
 // SetName sets the name of the Adaptor
 func (c *Adaptor) SetName(n string) { c.name = n }
 
-// Connect initializes the board
+// Connect initializes the board. It probes /sys/class/pwm for the PWM controller exposed by
+// this board's device tree, so PWMPin doesn't have to assume pwmchip0 is always correct.
 func (c *Adaptor) Connect() (err error) {
+	c.pwmChip = probePWMChip()
 	return nil
 }
 
+// probePWMChip returns the index of the lowest-numbered pwmchipN found under
+// /sys/class/pwm, or 0 if none is found (e.g. when not running on real hardware).
+func probePWMChip() int {
+	matches, err := filepath.Glob("/sys/class/pwm/pwmchip*")
+	if err != nil || len(matches) == 0 {
+		return 0
+	}
+
+	chip := -1
+	for _, m := range matches {
+		var n int
+		if _, err := fmt.Sscanf(filepath.Base(m), "pwmchip%d", &n); err != nil {
+			continue
+		}
+		if chip < 0 || n < chip {
+			chip = n
+		}
+	}
+	if chip < 0 {
+		return 0
+	}
+	return chip
+}
+
 // Finalize closes connection to board and pins
 func (c *Adaptor) Finalize() (err error) {
 	c.mutex.Lock()
@@ -119,7 +170,7 @@ func (c *Adaptor) GetConnection(address int, bus int) (connection i2c.Connection
 
 // GetDefaultBus returns the default i2c bus for this platform
 func (c *Adaptor) GetDefaultBus() int {
-	return 0
+	return c.defaultI2CBus
 }
 
 // DigitalPin returns matched digitalPin for specified values
@@ -155,7 +206,12 @@ func (c *Adaptor) PWMPin(pin string) (sysfsPin sysfs.PWMPinner, err error) {
 	sysPin := c.pinmap[pin]
 	if sysPin.pwmPin != -1 {
 		if c.pwmPins[sysPin.pwmPin] == nil {
-			newPin := sysfs.NewPWMPin(sysPin.pwmPin)
+			chip := c.pwmChip
+			if chip < 0 {
+				// Connect() was never called (e.g. in tests); fall back to probing now.
+				chip = probePWMChip()
+			}
+			newPin := sysfs.NewPWMPin(chip, sysPin.pwmPin)
 			if err = newPin.Export(); err != nil {
 				return
 			}
@@ -169,7 +225,7 @@ func (c *Adaptor) PWMPin(pin string) (sysfsPin sysfs.PWMPinner, err error) {
 			if err = newPin.Enable(true); err != nil {
 				return
 			}
-			if err = newPin.SetPeriod(10000000); err != nil {
+			if err = newPin.SetPeriod(c.pwmPeriod); err != nil {
 				return
 			}
 			c.pwmPins[sysPin.pwmPin] = newPin
@@ -215,7 +271,7 @@ func (c *Adaptor) ServoWrite(pin string, angle byte) (err error) {
 }
 
 func (c *Adaptor) translatePin(pin string) (i int, err error) {
-	if val, ok := pins[pin]; ok {
+	if val, ok := c.pinmap[pin]; ok {
 		i = val.pin
 	} else {
 		err = errors.New("Not a valid pin")
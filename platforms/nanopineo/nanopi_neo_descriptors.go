@@ -0,0 +1,88 @@
+package nanopineo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Descriptor captures everything that differs between members of the NanoPi family: the
+// device-tree "compatible" string that identifies the board, its pin map and its default I2C
+// bus. This mirrors the Describer/Descriptor registry the embd project uses to split
+// host-specific drivers, so users can support boards this package doesn't know about by
+// calling RegisterDescriptor instead of editing it.
+type Descriptor struct {
+	Board         string
+	Compatible    string
+	Pins          map[string]sysfsPin
+	DefaultI2CBus int
+}
+
+var descriptors = map[string]Descriptor{}
+
+// RegisterDescriptor adds (or replaces) the Descriptor for a NanoPi board, keyed by its
+// device-tree compatible string.
+func RegisterDescriptor(d Descriptor) {
+	descriptors[d.Compatible] = d
+}
+
+func init() {
+	RegisterDescriptor(Descriptor{
+		Board:         "nanopi-neo",
+		Compatible:    "friendlyarm,nanopi-neo",
+		Pins:          pins,
+		DefaultI2CBus: 0,
+	})
+	RegisterDescriptor(Descriptor{
+		Board:         "nanopi-neo-air",
+		Compatible:    "friendlyarm,nanopi-neo-air",
+		Pins:          neoAirPins,
+		DefaultI2CBus: 0,
+	})
+	RegisterDescriptor(Descriptor{
+		Board:         "nanopi-neo2",
+		Compatible:    "friendlyarm,nanopi-neo2",
+		Pins:          neo2Pins,
+		DefaultI2CBus: 0,
+	})
+}
+
+// deviceTreeCompatiblePaths are tried in order; both expose the same NUL-separated list of
+// compatible strings, most specific board first.
+var deviceTreeCompatiblePaths = []string{
+	"/proc/device-tree/compatible",
+	"/sys/firmware/devicetree/base/compatible",
+}
+
+// detectCompatible reads the board's device-tree "compatible" property and returns its most
+// specific entry.
+func detectCompatible() (string, error) {
+	var readErr error
+	for _, path := range deviceTreeCompatiblePaths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			readErr = err
+			continue
+		}
+		for _, entry := range strings.Split(string(data), "\x00") {
+			if entry != "" {
+				return entry, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not read device-tree compatible string: %v", readErr)
+}
+
+// lookupDescriptor detects the running board and returns its registered Descriptor.
+func lookupDescriptor() (Descriptor, error) {
+	compatible, err := detectCompatible()
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	d, ok := descriptors[compatible]
+	if !ok {
+		return Descriptor{}, fmt.Errorf("no NanoPi Descriptor registered for board %q", compatible)
+	}
+	return d, nil
+}
@@ -0,0 +1,50 @@
+package nanopineo
+
+import "testing"
+
+func TestRegisteredDescriptors(t *testing.T) {
+	cases := []struct {
+		compatible string
+		board      string
+		pins       map[string]sysfsPin
+	}{
+		{"friendlyarm,nanopi-neo", "nanopi-neo", pins},
+		{"friendlyarm,nanopi-neo-air", "nanopi-neo-air", neoAirPins},
+		{"friendlyarm,nanopi-neo2", "nanopi-neo2", neo2Pins},
+	}
+
+	for _, c := range cases {
+		d, ok := descriptors[c.compatible]
+		if !ok {
+			t.Fatalf("no Descriptor registered for %q", c.compatible)
+		}
+		if d.Board != c.board {
+			t.Errorf("descriptors[%q].Board = %q, want %q", c.compatible, d.Board, c.board)
+		}
+		if d.DefaultI2CBus != 0 {
+			t.Errorf("descriptors[%q].DefaultI2CBus = %d, want 0", c.compatible, d.DefaultI2CBus)
+		}
+		if len(d.Pins) != len(c.pins) {
+			t.Errorf("descriptors[%q].Pins has %d entries, want %d", c.compatible, len(d.Pins), len(c.pins))
+		}
+	}
+}
+
+func TestRegisterDescriptorAddsBoard(t *testing.T) {
+	custom := Descriptor{
+		Board:         "nanopi-custom",
+		Compatible:    "friendlyarm,nanopi-custom",
+		Pins:          map[string]sysfsPin{"GPIOA0": {pin: 0, pwmPin: -1}},
+		DefaultI2CBus: 1,
+	}
+	RegisterDescriptor(custom)
+	defer delete(descriptors, custom.Compatible)
+
+	d, ok := descriptors[custom.Compatible]
+	if !ok {
+		t.Fatal("RegisterDescriptor did not register the custom board")
+	}
+	if d.Board != custom.Board || d.DefaultI2CBus != custom.DefaultI2CBus || len(d.Pins) != len(custom.Pins) {
+		t.Errorf("descriptors[%q] = %+v, want %+v", custom.Compatible, d, custom)
+	}
+}
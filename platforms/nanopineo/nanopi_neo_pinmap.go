@@ -1,5 +1,6 @@
 package nanopineo
 
+// pins is the GPIOxx -> sysfs pin map for the original NanoPi Neo (Allwinner H3).
 var pins = map[string]sysfsPin{
 	"GPIOG11": {
 		pin:    203,
@@ -33,4 +34,67 @@ var pins = map[string]sysfsPin{
 		pin:    16,
 		pwmPin: -1,
 	},
+	// PA5, the H3's PWM0 pin, exposed as pwm0 of the pwmchip that the adaptor probes for.
+	"GPIOA5": {
+		pin:    17,
+		pwmPin: 0,
+	},
+}
+
+// neoAirPins is the GPIOxx -> sysfs pin map for the NanoPi Neo Air (Allwinner H3, same SoC as
+// the Neo but a smaller pin header).
+var neoAirPins = map[string]sysfsPin{
+	"GPIOA2": {
+		pin:    14,
+		pwmPin: -1,
+	},
+	"GPIOA3": {
+		pin:    16,
+		pwmPin: -1,
+	},
+	"GPIOA6": {
+		pin:    6,
+		pwmPin: -1,
+	},
+	"GPIOA18": {
+		pin:    18,
+		pwmPin: -1,
+	},
+	"GPIOG6": {
+		pin:    198,
+		pwmPin: -1,
+	},
+	"GPIOG7": {
+		pin:    199,
+		pwmPin: -1,
+	},
+}
+
+// neo2Pins is the GPIOxx -> sysfs pin map for the NanoPi NEO2 (Allwinner H5), which uses a
+// different GPIO base and bank layout than the H3 boards.
+var neo2Pins = map[string]sysfsPin{
+	"GPIOA2": {
+		pin:    2,
+		pwmPin: -1,
+	},
+	"GPIOA3": {
+		pin:    3,
+		pwmPin: -1,
+	},
+	"GPIOA6": {
+		pin:    6,
+		pwmPin: -1,
+	},
+	"GPIOC0": {
+		pin:    64,
+		pwmPin: -1,
+	},
+	"GPIOC1": {
+		pin:    65,
+		pwmPin: -1,
+	},
+	"GPIODV11": {
+		pin:    107,
+		pwmPin: -1,
+	},
 }
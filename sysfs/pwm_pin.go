@@ -0,0 +1,180 @@
+package sysfs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// PWMPinner is the interface for sysfs PWM interactions.
+type PWMPinner interface {
+	// Export exports the pin for use by the operating system
+	Export() error
+	// Unexport unexports the pin and releases the pin from the operating system
+	Unexport() error
+	// Enable enables/disables the PWM pin
+	Enable(bool) (err error)
+	// Polarity returns the polarity either normal or inverted
+	Polarity() (polarity string, err error)
+	// InvertPolarity sets the polarity to inverted if called with true
+	InvertPolarity(invert bool) (err error)
+	// Period returns the current PWM period for pin
+	Period() (period uint32, err error)
+	// SetPeriod sets the current PWM period for pin
+	SetPeriod(period uint32) (err error)
+	// DutyCycle returns the duty cycle for the pin
+	DutyCycle() (duty uint32, err error)
+	// SetDutyCycle writes the duty cycle to the pin
+	SetDutyCycle(duty uint32) (err error)
+}
+
+// PWMPin is a PWM pin exported over /sys/class/pwm/pwmchipN.
+type PWMPin struct {
+	chip    int
+	pin     string
+	path    string
+	enabled bool
+}
+
+// NewPWMPin returns a new PWMPin for the given pin on the given pwmchip index, e.g.
+// NewPWMPin(0, 1) talks to /sys/class/pwm/pwmchip0/pwm1. Platforms with a single PWM
+// controller can pass 0.
+func NewPWMPin(chip int, pin int) *PWMPin {
+	return &PWMPin{
+		chip:    chip,
+		pin:     strconv.Itoa(pin),
+		enabled: false,
+		path:    fmt.Sprintf("/sys/class/pwm/pwmchip%d", chip),
+	}
+}
+
+// Export writes pin to pwm export path.
+func (p *PWMPin) Export() error {
+	_, err := writePwmFile(p.pwmExportPath(), []byte(p.pin))
+	if err != nil {
+		// If the pin is already exported, writing "export" again fails with EBUSY; ignore it.
+		if e, ok := err.(*os.PathError); !ok || e.Err != syscall.EBUSY {
+			return err
+		}
+	}
+
+	// Pause to avoid a race with any udev rule that changes file permissions on newly
+	// exported pins; a common circumstance when running as a non-root user.
+	time.Sleep(100 * time.Millisecond)
+	return nil
+}
+
+// Unexport writes pin to pwm unexport path.
+func (p *PWMPin) Unexport() (err error) {
+	_, err = writePwmFile(p.pwmUnexportPath(), []byte(p.pin))
+	return
+}
+
+// Enable writes value to pwm enable path.
+func (p *PWMPin) Enable(enable bool) (err error) {
+	if p.enabled != enable {
+		p.enabled = enable
+		enableVal := 0
+		if enable {
+			enableVal = 1
+		}
+		_, err = writePwmFile(p.pwmEnablePath(), []byte(fmt.Sprintf("%v", enableVal)))
+	}
+	return
+}
+
+// Polarity returns current polarity value.
+func (p *PWMPin) Polarity() (polarity string, err error) {
+	buf, err := readPwmFile(p.pwmPolarityPath())
+	if err != nil {
+		return
+	}
+	if len(buf) == 0 {
+		return "", nil
+	}
+	return string(buf), nil
+}
+
+// InvertPolarity writes value to pwm polarity path.
+func (p *PWMPin) InvertPolarity(invert bool) (err error) {
+	if p.enabled {
+		return fmt.Errorf("Cannot set PWM polarity when enabled")
+	}
+	polarity := "normal"
+	if invert {
+		polarity = "inverted"
+	}
+	_, err = writePwmFile(p.pwmPolarityPath(), []byte(polarity))
+	return
+}
+
+// Period reads from pwm period path and returns value in nanoseconds.
+func (p *PWMPin) Period() (period uint32, err error) {
+	buf, err := readPwmFile(p.pwmPeriodPath())
+	if err != nil {
+		return
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	val, err := strconv.Atoi(string(bytes.TrimSpace(buf)))
+	return uint32(val), err
+}
+
+// SetPeriod sets pwm period in nanoseconds.
+func (p *PWMPin) SetPeriod(period uint32) (err error) {
+	_, err = writePwmFile(p.pwmPeriodPath(), []byte(fmt.Sprintf("%v", period)))
+	return
+}
+
+// DutyCycle reads from pwm duty cycle path and returns value in nanoseconds.
+func (p *PWMPin) DutyCycle() (duty uint32, err error) {
+	buf, err := readPwmFile(p.pwmDutyCyclePath())
+	if err != nil {
+		return
+	}
+	val, err := strconv.Atoi(string(bytes.TrimSpace(buf)))
+	return uint32(val), err
+}
+
+// SetDutyCycle writes value, in nanoseconds, to the pwm duty cycle path.
+func (p *PWMPin) SetDutyCycle(duty uint32) (err error) {
+	_, err = writePwmFile(p.pwmDutyCyclePath(), []byte(fmt.Sprintf("%v", duty)))
+	return
+}
+
+func (p *PWMPin) pwmExportPath() string   { return p.path + "/export" }
+func (p *PWMPin) pwmUnexportPath() string { return p.path + "/unexport" }
+func (p *PWMPin) pwmDutyCyclePath() string {
+	return p.path + "/pwm" + p.pin + "/duty_cycle"
+}
+func (p *PWMPin) pwmPeriodPath() string   { return p.path + "/pwm" + p.pin + "/period" }
+func (p *PWMPin) pwmEnablePath() string   { return p.path + "/pwm" + p.pin + "/enable" }
+func (p *PWMPin) pwmPolarityPath() string { return p.path + "/pwm" + p.pin + "/polarity" }
+
+func writePwmFile(path string, data []byte) (int, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return file.Write(data)
+}
+
+func readPwmFile(path string) ([]byte, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY, 0644)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 200)
+	n, err := file.Read(buf)
+	if n == 0 {
+		return []byte{}, err
+	}
+	return buf[:n], err
+}